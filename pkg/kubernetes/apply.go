@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// yamlDocumentSeparator splits a multi-document YAML manifest into its individual documents.
+var yamlDocumentSeparator = regexp.MustCompile(`\n---\s*\n`)
+
+// ApplyYAML server-side applies every document in manifest, using fieldManager to own the
+// fields it sets and force to take ownership of fields currently managed by someone else.
+func (c *kubernetesClientSetClient) ApplyYAML(manifest []byte, fieldManager string, force bool) error {
+	docs := splitYAMLDocuments(manifest)
+
+	var applyErrs []error
+	for _, doc := range docs {
+		obj, err := decodeUnstructured(doc)
+		if err != nil {
+			applyErrs = append(applyErrs, err)
+			continue
+		}
+		if err := c.applyUnstructuredSSA(obj, fieldManager, force); err != nil {
+			applyErrs = append(applyErrs, errors.Wrapf(err, "applying %s %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName()))
+		}
+	}
+	return utilerrors.NewAggregate(applyErrs)
+}
+
+// DeleteYAML deletes every document in manifest, resolving each document's REST mapping the
+// same way ApplyYAML does. Missing resources are treated as already deleted.
+func (c *kubernetesClientSetClient) DeleteYAML(manifest []byte) error {
+	docs := splitYAMLDocuments(manifest)
+
+	var deleteErrs []error
+	for _, doc := range docs {
+		obj, err := decodeUnstructured(doc)
+		if err != nil {
+			deleteErrs = append(deleteErrs, err)
+			continue
+		}
+		if err := c.deleteUnstructured(obj); err != nil && !apierrors.IsNotFound(err) {
+			deleteErrs = append(deleteErrs, errors.Wrapf(err, "deleting %s %s/%s", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName()))
+		}
+	}
+	return utilerrors.NewAggregate(deleteErrs)
+}
+
+func splitYAMLDocuments(manifest []byte) [][]byte {
+	var docs [][]byte
+	for _, doc := range yamlDocumentSeparator.Split(string(manifest), -1) {
+		trimmed := bytes.TrimSpace([]byte(doc))
+		if len(trimmed) == 0 {
+			continue
+		}
+		docs = append(docs, trimmed)
+	}
+	return docs
+}
+
+func decodeUnstructured(doc []byte) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+		return nil, errors.Wrap(err, "decoding manifest document")
+	}
+	return obj, nil
+}
+
+// applyUnstructuredSSA issues a server-side apply Patch for obj, routing to the namespaced
+// or cluster-scoped dynamic client depending on the resource's REST scope.
+func (c *kubernetesClientSetClient) applyUnstructuredSSA(obj *unstructured.Unstructured, fieldManager string, force bool) error {
+	mapping, err := c.restMapper().RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+	if err != nil {
+		return errors.Wrapf(err, "resolving REST mapping for %s", obj.GroupVersionKind())
+	}
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return errors.Wrap(err, "marshalling manifest document")
+	}
+
+	patchOpts := metav1.PatchOptions{FieldManager: fieldManager, Force: &force}
+	_, err = c.resourceInterfaceFor(mapping, obj.GetNamespace()).Patch(obj.GetName(), types.ApplyPatchType, data, patchOpts)
+	return err
+}
+
+func (c *kubernetesClientSetClient) deleteUnstructured(obj *unstructured.Unstructured) error {
+	mapping, err := c.restMapper().RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+	if err != nil {
+		return errors.Wrapf(err, "resolving REST mapping for %s", obj.GroupVersionKind())
+	}
+	return c.resourceInterfaceFor(mapping, obj.GetNamespace()).Delete(obj.GetName(), &metav1.DeleteOptions{})
+}
+
+// resourceInterfaceFor returns the dynamic resource client for mapping, scoped to namespace
+// only when the resource is namespaced.
+func (c *kubernetesClientSetClient) resourceInterfaceFor(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return c.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return c.dynamicClient.Resource(mapping.Resource)
+}
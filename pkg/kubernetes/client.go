@@ -4,6 +4,7 @@
 package kubernetes
 
 import (
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -11,11 +12,15 @@ import (
 	v1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -27,8 +32,14 @@ const (
 
 // kubernetesClientSetClient is a Kubernetes client hooked up to a live api server.
 type kubernetesClientSetClient struct {
-	clientset         *kubernetes.Clientset
-	interval, timeout time.Duration
+	clientset           kubernetes.Interface
+	dynamicClient       dynamic.Interface
+	apiextensionsClient apiextensionsclientset.Interface
+	restConfig          *rest.Config
+	interval, timeout   time.Duration
+
+	restMapperOnce   sync.Once
+	cachedRESTMapper meta.RESTMapper
 }
 
 // TODO This contructor does not follow best practices
@@ -47,7 +58,29 @@ func NewClient(apiserverURL, kubeConfig string, interval, timeout time.Duration)
 	if err != nil {
 		return nil, err
 	}
-	return &kubernetesClientSetClient{clientset: clientset, interval: interval, timeout: timeout}, nil
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	apiextensionsClient, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromInterfaces(clientset, dynamicClient, apiextensionsClient, config, interval, timeout), nil
+}
+
+// NewClientFromInterfaces returns a Client backed by the given clientset, dynamic, and
+// apiextensions interfaces. restConfig may be nil if the caller never exercises
+// ExecInPod/PortForward.
+func NewClientFromInterfaces(clientset kubernetes.Interface, dynamicClient dynamic.Interface, apiextensionsClient apiextensionsclientset.Interface, restConfig *rest.Config, interval, timeout time.Duration) Client {
+	return &kubernetesClientSetClient{
+		clientset:           clientset,
+		dynamicClient:       dynamicClient,
+		apiextensionsClient: apiextensionsClient,
+		restConfig:          restConfig,
+		interval:            interval,
+		timeout:             timeout,
+	}
 }
 
 // ListPods returns Pods running on the passed in node.
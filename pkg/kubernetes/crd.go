@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ApplyUnstructured creates obj if it does not exist, or updates it in place if it does,
+// resolving its GroupVersionResource via the cluster's discovery information.
+func (c *kubernetesClientSetClient) ApplyUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	mapping, err := c.restMapper().RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving REST mapping for %s", obj.GroupVersionKind())
+	}
+
+	resourceClient := c.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+
+	existing, err := resourceClient.Get(obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return resourceClient.Create(obj, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting %s %s", obj.GroupVersionKind(), obj.GetName())
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return resourceClient.Update(obj, metav1.UpdateOptions{})
+}
+
+// GetCRD returns the named CustomResourceDefinition.
+func (c *kubernetesClientSetClient) GetCRD(name string) (*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	return c.apiextensionsClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(name, metav1.GetOptions{})
+}
+
+// WaitForCRDEstablished polls the named CustomResourceDefinition until its Established
+// condition is true, or timeout elapses. Addons that install CRDs must wait for this before
+// creating resources of the new type.
+func (c *kubernetesClientSetClient) WaitForCRDEstablished(name string, timeout time.Duration) error {
+	return wait.PollImmediate(c.interval, timeout, func() (bool, error) {
+		crd, err := c.GetCRD(name)
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range crd.Status.Conditions {
+			if cond.Type == apiextensionsv1beta1.Established && cond.Status == apiextensionsv1beta1.ConditionTrue {
+				return true, nil
+			}
+			if cond.Type == apiextensionsv1beta1.NamesAccepted && cond.Status == apiextensionsv1beta1.ConditionFalse {
+				return false, errors.Errorf("CRD %s name conflict: %s", name, cond.Reason)
+			}
+		}
+		return false, nil
+	})
+}
+
+// ListByGVR lists resources of the given GroupVersionResource in namespace, matching opts.
+// An empty namespace lists cluster-scoped resources or across all namespaces, per the usual
+// dynamic client convention.
+func (c *kubernetesClientSetClient) ListByGVR(gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return c.dynamicClient.Resource(gvr).Namespace(namespace).List(opts)
+}
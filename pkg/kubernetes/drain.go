@@ -0,0 +1,299 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// localStorageEmptyDirAnnotation marks a pod as locally bound via the (deprecated)
+	// safe-to-evict annotation, mirroring kubectl drain's treatment of local storage.
+	localStorageEmptyDirAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+	evictionMaxRetries    = 10
+	evictionBackoffBase   = 1 * time.Second
+	evictionBackoffFactor = 2.0
+	evictionBackoffCap    = 30 * time.Second
+	defaultDrainWorkers   = 10
+)
+
+// DrainOptions configures the behavior of Drain, mirroring kubectl's `kubectl drain` flags.
+type DrainOptions struct {
+	// Force allows deletion of pods not managed by a ReplicationController, ReplicaSet,
+	// Job, DaemonSet or StatefulSet.
+	Force bool
+	// IgnoreAllDaemonSets ignores DaemonSet-managed pods rather than failing on them.
+	IgnoreAllDaemonSets bool
+	// DeleteEmptyDirData accepts eviction/deletion of pods using emptyDir, even though the
+	// data will be deleted once the node is drained.
+	DeleteEmptyDirData bool
+	// GracePeriodSeconds overrides each pod's terminationGracePeriodSeconds, including to 0
+	// for an immediate force-delete. A nil value (the default) means "use the pod's own
+	// grace period".
+	GracePeriodSeconds *int
+	// Timeout is the total time to wait for the drain to complete.
+	Timeout time.Duration
+	// SkipWaitForDeleteTimeoutSeconds skips waiting for a pod's deletion/eviction to be
+	// confirmed if the pod's deletion timestamp is older than this many seconds, to avoid
+	// blocking on pods stuck terminating behind a dead kubelet.
+	SkipWaitForDeleteTimeoutSeconds int
+	// PodSelector restricts eviction to pods matching this label selector. An empty
+	// selector matches all pods on the node.
+	PodSelector string
+	// DisableEviction forces use of direct pod deletion instead of the eviction API, even
+	// if the api server supports eviction.
+	DisableEviction bool
+	// Workers caps how many pods are evicted/deleted in parallel. Defaults to 10.
+	Workers int
+}
+
+// PodDeleteStatus describes the outcome of classifying or draining a single pod.
+type PodDeleteStatus struct {
+	Pod     v1.Pod
+	Delete  bool
+	Reason  string
+	Message string
+	Error   error
+}
+
+// DrainReport is the structured result of a Drain call.
+type DrainReport struct {
+	// Evicted are pods successfully evicted or deleted from the node.
+	Evicted []v1.Pod
+	// Skipped are pods intentionally left running, with a reason (mirror pod, daemonset, etc).
+	Skipped []PodDeleteStatus
+	// Failed are pods that were eligible for eviction/deletion but could not be removed.
+	Failed []PodDeleteStatus
+}
+
+// Drain evicts or deletes every evictable pod on node, following kubectl drain semantics:
+// mirror pods are left alone, DaemonSet pods and pods with local storage are skipped unless
+// the caller opts in, and unreplicated pods require Force. Evictable pods are removed in
+// parallel via a worker pool, retrying on 429 TooManyRequests (PDB violations) with
+// exponential backoff.
+func (c *kubernetesClientSetClient) Drain(node *v1.Node, opts *DrainOptions) (*DrainReport, error) {
+	if opts == nil {
+		opts = &DrainOptions{}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultDrainWorkers
+	}
+
+	listOpts := metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + node.Name,
+		LabelSelector: opts.PodSelector,
+	}
+	podList, err := c.clientset.CoreV1().Pods(metav1.NamespaceAll).List(listOpts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing pods on node %s", node.Name)
+	}
+
+	report := &DrainReport{}
+	var toEvict []v1.Pod
+	for _, pod := range podList.Items {
+		status := classifyPod(pod, opts)
+		if status.Error != nil {
+			report.Failed = append(report.Failed, status)
+			continue
+		}
+		if !status.Delete {
+			report.Skipped = append(report.Skipped, status)
+			continue
+		}
+		toEvict = append(toEvict, pod)
+	}
+
+	policyGroupVersion, err := c.SupportEviction()
+	if err != nil {
+		return report, errors.Wrap(err, "checking eviction support")
+	}
+	useEviction := policyGroupVersion != "" && !opts.DisableEviction
+
+	evicted, failed := c.evictPods(toEvict, policyGroupVersion, useEviction, workers, opts)
+	report.Evicted = append(report.Evicted, evicted...)
+	report.Failed = append(report.Failed, failed...)
+
+	if err := c.waitForDrainedWithTimeout(report.Evicted, useEviction, opts); err != nil {
+		return report, errors.Wrap(err, "waiting for pods to terminate")
+	}
+
+	if len(report.Failed) > 0 {
+		var aggregate []error
+		for _, f := range report.Failed {
+			aggregate = append(aggregate, fmt.Errorf("%s/%s: %s", f.Pod.Namespace, f.Pod.Name, f.Error))
+		}
+		return report, utilerrors.NewAggregate(aggregate)
+	}
+	return report, nil
+}
+
+// classifyPod decides whether a pod should be evicted/deleted, skipped, or rejected, per
+// kubectl drain semantics.
+func classifyPod(pod v1.Pod, opts *DrainOptions) PodDeleteStatus {
+	if _, ok := pod.ObjectMeta.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return PodDeleteStatus{Pod: pod, Delete: false, Reason: "MirrorPod", Message: "skipping mirror pod"}
+	}
+
+	controllerRef := metav1.GetControllerOf(&pod)
+	if controllerRef != nil && controllerRef.Kind == "DaemonSet" {
+		if !opts.IgnoreAllDaemonSets {
+			return PodDeleteStatus{Pod: pod, Delete: false, Reason: "DaemonSet",
+				Error: fmt.Errorf("pod %s is managed by a DaemonSet, set IgnoreAllDaemonSets to proceed", pod.Name)}
+		}
+		return PodDeleteStatus{Pod: pod, Delete: false, Reason: "DaemonSet", Message: "ignoring DaemonSet-managed pod"}
+	}
+
+	if hasLocalStorage(pod) && !opts.DeleteEmptyDirData {
+		return PodDeleteStatus{Pod: pod, Delete: false, Reason: "LocalStorage",
+			Error: fmt.Errorf("pod %s uses local storage, set DeleteEmptyDirData to proceed", pod.Name)}
+	}
+
+	if controllerRef == nil && !opts.Force {
+		return PodDeleteStatus{Pod: pod, Delete: false, Reason: "Unreplicated",
+			Error: fmt.Errorf("pod %s is not managed by a controller, set Force to proceed", pod.Name)}
+	}
+
+	return PodDeleteStatus{Pod: pod, Delete: true, Reason: "Evictable"}
+}
+
+func hasLocalStorage(pod v1.Pod) bool {
+	if pod.ObjectMeta.Annotations[localStorageEmptyDirAnnotation] == "true" {
+		return false
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// evictPods removes pods in parallel using a bounded worker pool, falling back to direct
+// deletion when eviction is unavailable or disabled.
+func (c *kubernetesClientSetClient) evictPods(pods []v1.Pod, policyGroupVersion string, useEviction bool, workers int, opts *DrainOptions) ([]v1.Pod, []PodDeleteStatus) {
+	var (
+		mu           sync.Mutex
+		evicted      []v1.Pod
+		failed       []PodDeleteStatus
+		wg           sync.WaitGroup
+		podCh        = make(chan v1.Pod)
+		deleteOption = gracePeriodDeleteOptions(opts.GracePeriodSeconds)
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pod := range podCh {
+				var err error
+				if useEviction {
+					err = c.evictPodWithBackoff(pod, policyGroupVersion)
+				} else {
+					err = c.clientset.CoreV1().Pods(pod.Namespace).Delete(pod.Name, deleteOption)
+				}
+				mu.Lock()
+				if err != nil && !apierrors.IsNotFound(err) {
+					failed = append(failed, PodDeleteStatus{Pod: pod, Reason: "EvictionFailed", Error: err})
+				} else {
+					evicted = append(evicted, pod)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, pod := range pods {
+		podCh <- pod
+	}
+	close(podCh)
+	wg.Wait()
+
+	return evicted, failed
+}
+
+// gracePeriodDeleteOptions builds DeleteOptions honoring an overridden grace period, leaving
+// the pod's own grace period untouched when gracePeriodSeconds is nil.
+func gracePeriodDeleteOptions(gracePeriodSeconds *int) *metav1.DeleteOptions {
+	if gracePeriodSeconds == nil {
+		return &metav1.DeleteOptions{}
+	}
+	grace := int64(*gracePeriodSeconds)
+	return &metav1.DeleteOptions{GracePeriodSeconds: &grace}
+}
+
+// waitForDrainedWithTimeout confirms evicted pods are actually removed from the api server,
+// bounded by opts.Timeout and skipping pods whose deletion has been stuck for longer than
+// SkipWaitForDeleteTimeoutSeconds (e.g. behind an unresponsive kubelet).
+func (c *kubernetesClientSetClient) waitForDrainedWithTimeout(pods []v1.Pod, useEviction bool, opts *DrainOptions) error {
+	if len(pods) == 0 {
+		return nil
+	}
+
+	var pending []v1.Pod
+	for _, pod := range pods {
+		if opts.SkipWaitForDeleteTimeoutSeconds > 0 && pod.DeletionTimestamp != nil {
+			stuckSince := time.Since(pod.DeletionTimestamp.Time)
+			if stuckSince > time.Duration(opts.SkipWaitForDeleteTimeoutSeconds)*time.Second {
+				continue
+			}
+		}
+		pending = append(pending, pod)
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
+	logger := log.WithField("node", pods[0].Spec.NodeName)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.WaitForDelete(logger, pending, useEviction)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s waiting for %d pod(s) to terminate", timeout, len(pending))
+	}
+}
+
+// evictPodWithBackoff retries EvictPod on 429 TooManyRequests, which the api server returns
+// while a PodDisruptionBudget would otherwise be violated.
+func (c *kubernetesClientSetClient) evictPodWithBackoff(pod v1.Pod, policyGroupVersion string) error {
+	backoff := wait.Backoff{
+		Duration: evictionBackoffBase,
+		Factor:   evictionBackoffFactor,
+		Steps:    evictionMaxRetries,
+		Cap:      evictionBackoffCap,
+	}
+	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+		err := c.EvictPod(&pod, policyGroupVersion)
+		switch {
+		case err == nil:
+			return true, nil
+		case apierrors.IsTooManyRequests(err):
+			log.Debugf("eviction of pod %s/%s blocked by PodDisruptionBudget, retrying", pod.Namespace, pod.Name)
+			return false, nil
+		case apierrors.IsNotFound(err):
+			return true, nil
+		default:
+			return false, err
+		}
+	})
+}
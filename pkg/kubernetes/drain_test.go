@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func controllerOwnerRef(kind string) metav1.OwnerReference {
+	isController := true
+	return metav1.OwnerReference{Kind: kind, Name: "owner", UID: "owner-uid", Controller: &isController}
+}
+
+func TestClassifyPod(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        v1.Pod
+		opts       *DrainOptions
+		wantDelete bool
+		wantReason string
+		wantErr    bool
+	}{
+		{
+			name:       "mirror pod is skipped without error",
+			pod:        v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.MirrorPodAnnotationKey: ""}}},
+			opts:       &DrainOptions{},
+			wantDelete: false,
+			wantReason: "MirrorPod",
+		},
+		{
+			name:       "daemonset pod fails without IgnoreAllDaemonSets",
+			pod:        v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("DaemonSet")}}},
+			opts:       &DrainOptions{},
+			wantDelete: false,
+			wantReason: "DaemonSet",
+			wantErr:    true,
+		},
+		{
+			name:       "daemonset pod is skipped with IgnoreAllDaemonSets",
+			pod:        v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("DaemonSet")}}},
+			opts:       &DrainOptions{IgnoreAllDaemonSets: true},
+			wantDelete: false,
+			wantReason: "DaemonSet",
+		},
+		{
+			name: "local storage pod fails without DeleteEmptyDirData",
+			pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("ReplicaSet")}},
+				Spec:       v1.PodSpec{Volumes: []v1.Volume{{VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}},
+			},
+			opts:       &DrainOptions{},
+			wantDelete: false,
+			wantReason: "LocalStorage",
+			wantErr:    true,
+		},
+		{
+			name: "local storage pod is evictable with DeleteEmptyDirData",
+			pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("ReplicaSet")}},
+				Spec:       v1.PodSpec{Volumes: []v1.Volume{{VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}},
+			},
+			opts:       &DrainOptions{DeleteEmptyDirData: true},
+			wantDelete: true,
+			wantReason: "Evictable",
+		},
+		{
+			name:       "unreplicated pod fails without Force",
+			pod:        v1.Pod{},
+			opts:       &DrainOptions{},
+			wantDelete: false,
+			wantReason: "Unreplicated",
+			wantErr:    true,
+		},
+		{
+			name:       "unreplicated pod is evictable with Force",
+			pod:        v1.Pod{},
+			opts:       &DrainOptions{Force: true},
+			wantDelete: true,
+			wantReason: "Evictable",
+		},
+		{
+			name:       "replicaset-owned pod is evictable",
+			pod:        v1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("ReplicaSet")}}},
+			opts:       &DrainOptions{},
+			wantDelete: true,
+			wantReason: "Evictable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := classifyPod(tt.pod, tt.opts)
+			if status.Delete != tt.wantDelete {
+				t.Errorf("Delete = %v, want %v", status.Delete, tt.wantDelete)
+			}
+			if status.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", status.Reason, tt.wantReason)
+			}
+			if (status.Error != nil) != tt.wantErr {
+				t.Errorf("Error = %v, wantErr %v", status.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGracePeriodDeleteOptions(t *testing.T) {
+	if opts := gracePeriodDeleteOptions(nil); opts.GracePeriodSeconds != nil {
+		t.Errorf("nil grace period should leave GracePeriodSeconds unset, got %v", *opts.GracePeriodSeconds)
+	}
+
+	zero := 0
+	opts := gracePeriodDeleteOptions(&zero)
+	if opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds != 0 {
+		t.Errorf("explicit 0 grace period must be reachable, got %v", opts.GracePeriodSeconds)
+	}
+
+	thirty := 30
+	opts = gracePeriodDeleteOptions(&thirty)
+	if opts.GracePeriodSeconds == nil || *opts.GracePeriodSeconds != 30 {
+		t.Errorf("GracePeriodSeconds = %v, want 30", opts.GracePeriodSeconds)
+	}
+}
+
+func TestDrainClassifiesPods(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+
+	daemonsetPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonset-pod", Namespace: "kube-system",
+			OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("DaemonSet")}},
+		Spec: v1.PodSpec{NodeName: node.Name},
+	}
+	unreplicatedPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "bare-pod", Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: node.Name},
+	}
+	evictablePod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-pod", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{controllerOwnerRef("ReplicaSet")}},
+		Spec: v1.PodSpec{NodeName: node.Name},
+	}
+
+	c := &kubernetesClientSetClient{
+		clientset: kubefake.NewSimpleClientset(&daemonsetPod, &unreplicatedPod, &evictablePod),
+		interval:  10 * time.Millisecond,
+		timeout:   time.Second,
+	}
+
+	report, err := c.Drain(node, &DrainOptions{IgnoreAllDaemonSets: true, Timeout: time.Second})
+	if err == nil {
+		t.Fatal("expected an error reporting the unreplicated pod's failure")
+	}
+
+	if len(report.Evicted) != 1 || report.Evicted[0].Name != evictablePod.Name {
+		t.Errorf("Evicted = %+v, want only %s", report.Evicted, evictablePod.Name)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Pod.Name != daemonsetPod.Name {
+		t.Errorf("Skipped = %+v, want only %s", report.Skipped, daemonsetPod.Name)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Pod.Name != unreplicatedPod.Name {
+		t.Errorf("Failed = %+v, want only %s", report.Failed, unreplicatedPod.Name)
+	}
+}
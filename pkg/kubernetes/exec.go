@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// ExecInPod runs cmd inside container of pod, streaming stdin/stdout/stderr over the api
+// server's exec subresource.
+func (c *kubernetesClientSetClient) ExecInPod(namespace, pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    stdout != nil,
+			Stderr:    stderr != nil,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.restConfig, "POST", req.URL())
+	if err != nil {
+		return errors.Wrapf(err, "building exec stream for pod %s/%s", namespace, pod)
+	}
+	return executor.Stream(remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
+// StreamPodLogs returns a stream of container's logs in pod. Callers must close the
+// returned ReadCloser.
+func (c *kubernetesClientSetClient) StreamPodLogs(namespace, pod, container string, opts *v1.PodLogOptions) (io.ReadCloser, error) {
+	if opts == nil {
+		opts = &v1.PodLogOptions{}
+	}
+	opts.Container = container
+	return c.clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream()
+}
+
+// PortForward forwards local ports to pod over the api server's portforward subresource,
+// blocking until stopCh is closed or an error occurs. Each entry in ports follows the
+// kubectl port-forward syntax, e.g. "8080:80".
+func (c *kubernetesClientSetClient) PortForward(namespace, pod string, ports []string, stopCh <-chan struct{}) error {
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.restConfig)
+	if err != nil {
+		return errors.Wrapf(err, "building portforward transport for pod %s/%s", namespace, pod)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	readyCh := make(chan struct{})
+	fw, err := portforward.New(dialer, ports, stopCh, readyCh, ioutil.Discard, os.Stderr)
+	if err != nil {
+		return errors.Wrapf(err, "setting up portforward to pod %s/%s", namespace, pod)
+	}
+	return fw.ForwardPorts()
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+// Package fake provides a fake.NewSimpleClientset-backed kubernetes.Client for unit testing
+// upgrade, scale, and addon code paths without a live api server.
+package fake
+
+import (
+	"time"
+
+	"github.com/Azure/aks-engine/pkg/kubernetes"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// NewClient returns a kubernetes.Client backed by fake clientset, dynamic, and apiextensions
+// implementations, seeded with objects.
+func NewClient(interval, timeout time.Duration, objects ...runtime.Object) kubernetes.Client {
+	return NewClientWithObjects(interval, timeout, objects, nil, nil, nil)
+}
+
+// NewClientWithObjects is NewClient, additionally seeding the dynamic client with
+// dynamicObjects and the apiextensions client with crds, for testing the
+// ApplyUnstructured/ApplyYAML/GetCRD/ListByGVR code paths. gvrToListKind resolves the List kind
+// for any GroupVersionResource the built-in scheme doesn't already know, such as a CRD-backed
+// custom resource.
+func NewClientWithObjects(interval, timeout time.Duration, objects, dynamicObjects, crds []runtime.Object, gvrToListKind map[schema.GroupVersionResource]string) kubernetes.Client {
+	clientset := fake.NewSimpleClientset(objects...)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, gvrToListKind, dynamicObjects...)
+	apiextensionsClient := apiextensionsfake.NewSimpleClientset(crds...)
+	return kubernetes.NewClientFromInterfaces(clientset, dynamicClient, apiextensionsClient, nil, interval, timeout)
+}
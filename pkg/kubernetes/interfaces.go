@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Client defines the interface to a Kubernetes client, hooked up to a live api server.
+type Client interface {
+	ListPods(node *v1.Node) (*v1.PodList, error)
+	ListAllPods() (*v1.PodList, error)
+	ListNodes() (*v1.NodeList, error)
+	ListNodesByOptions(opts metav1.ListOptions) (*v1.NodeList, error)
+	ListServiceAccounts(namespace string) (*v1.ServiceAccountList, error)
+	GetNode(name string) (*v1.Node, error)
+	UpdateNode(node *v1.Node) (*v1.Node, error)
+	DeleteNode(name string) error
+	DeleteServiceAccount(sa *v1.ServiceAccount) error
+	SupportEviction() (string, error)
+	DeleteClusterRole(role *rbacv1.ClusterRole) error
+	DeleteDaemonSet(daemonset *appsv1.DaemonSet) error
+	DeleteDeployment(deployment *appsv1.Deployment) error
+	DeletePod(pod *v1.Pod) error
+	EvictPod(pod *v1.Pod, policyGroupVersion string) error
+	WaitForDelete(logger *log.Entry, pods []v1.Pod, usingEviction bool) ([]v1.Pod, error)
+	GetDaemonSet(namespace, name string) (*appsv1.DaemonSet, error)
+	GetDeployment(namespace, name string) (*appsv1.Deployment, error)
+	UpdateDeployment(namespace string, deployment *appsv1.Deployment) (*appsv1.Deployment, error)
+	Drain(node *v1.Node, opts *DrainOptions) (*DrainReport, error)
+	ApplyUnstructured(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	GetCRD(name string) (*apiextensionsv1beta1.CustomResourceDefinition, error)
+	WaitForCRDEstablished(name string, timeout time.Duration) error
+	ListByGVR(gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	ApplyYAML(manifest []byte, fieldManager string, force bool) error
+	DeleteYAML(manifest []byte) error
+	ExecInPod(namespace, pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error
+	StreamPodLogs(namespace, pod, container string, opts *v1.PodLogOptions) (io.ReadCloser, error)
+	PortForward(namespace, pod string, ports []string, stopCh <-chan struct{}) error
+	RunWithLeaderElection(ctx context.Context, lockName, namespace string, fn func(ctx context.Context) error) error
+}
+
+var _ Client = &kubernetesClientSetClient{}
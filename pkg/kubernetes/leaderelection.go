@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+	// leaderElectionJitterFactor mirrors leaderelection.JitterFactor, which the library
+	// requires RenewDeadline to exceed RetryPeriod by, to keep leaseDurations' output valid
+	// regardless of what interval/timeout the caller configured the Client with.
+	leaderElectionJitterFactor = 1.2
+)
+
+// errNeverAcquiredLease is returned by RunWithLeaderElection when it stops contending for the
+// lease without ever acquiring it (e.g. ctx was cancelled while another replica held it), so
+// callers can't mistake "fn never ran" for "fn ran and succeeded".
+var errNeverAcquiredLease = errors.New("stopped contending for leader election lease without ever acquiring it")
+
+// RunWithLeaderElection runs fn only while holding the named Lease lock in namespace.
+func (c *kubernetesClientSetClient) RunWithLeaderElection(ctx context.Context, lockName, namespace string, fn func(ctx context.Context) error) error {
+	identity, err := os.Hostname()
+	if err != nil {
+		return errors.Wrap(err, "determining leader election identity")
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      lockName,
+			Namespace: namespace,
+		},
+		Client: c.clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	leaseDuration, renewDeadline, retryPeriod := leaseDurations(c.interval, c.timeout)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		started bool
+		fnErr   error
+	)
+	done := make(chan struct{})
+
+	leaderelection.RunOrDie(runCtx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			// leaderelection.Run forks this callback into its own goroutine and can
+			// return (e.g. if we lose the lease mid-run) well before fn finishes here, so
+			// mark started before running fn and signal done only once fn actually
+			// returns. RunWithLeaderElection below waits on done rather than trusting
+			// RunOrDie's return to mean fn is finished.
+			OnStartedLeading: func(leCtx context.Context) {
+				mu.Lock()
+				started = true
+				mu.Unlock()
+
+				fnErr = fn(leCtx)
+				close(done)
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				log.Infof("lost leadership for lease %s/%s", namespace, lockName)
+			},
+		},
+	})
+
+	mu.Lock()
+	isStarted := started
+	mu.Unlock()
+	if !isStarted {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return errNeverAcquiredLease
+	}
+	<-done
+	return fnErr
+}
+
+// leaseDurations derives LeaseDuration/RenewDeadline/RetryPeriod from the client's configured
+// interval/timeout, falling back to sane defaults, while always satisfying leaderelection's
+// invariant that RenewDeadline exceed RetryPeriod*JitterFactor (violating it makes
+// leaderelection.RunOrDie panic).
+func leaseDurations(interval, timeout time.Duration) (leaseDuration, renewDeadline, retryPeriod time.Duration) {
+	leaseDuration = timeout
+	if leaseDuration <= 0 {
+		leaseDuration = defaultLeaseDuration
+	}
+	retryPeriod = interval
+	if retryPeriod <= 0 {
+		retryPeriod = defaultRetryPeriod
+	}
+
+	minRenewDeadline := time.Duration(float64(retryPeriod) * leaderElectionJitterFactor)
+	renewDeadline = leaseDuration * 2 / 3
+	if renewDeadline <= minRenewDeadline {
+		renewDeadline = minRenewDeadline + retryPeriod
+	}
+	if leaseDuration <= renewDeadline {
+		leaseDuration = renewDeadline + retryPeriod
+	}
+	return leaseDuration, renewDeadline, retryPeriod
+}
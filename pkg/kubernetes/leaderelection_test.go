@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+// TestRunWithLeaderElectionWaitsForFn guards against the race fixed in the past: leaderelection
+// can return as soon as the lease is lost, well before the OnStartedLeading callback's fn
+// finishes running in its own goroutine. RunWithLeaderElection must still block until fn
+// actually returns.
+func TestRunWithLeaderElectionWaitsForFn(t *testing.T) {
+	c := &kubernetesClientSetClient{
+		clientset: kubefake.NewSimpleClientset(),
+		interval:  10 * time.Millisecond,
+		timeout:   50 * time.Millisecond,
+	}
+
+	const fnDuration = 150 * time.Millisecond
+	var fnCompleted int32
+
+	start := time.Now()
+	err := c.RunWithLeaderElection(context.Background(), "test-lock", "kube-system", func(ctx context.Context) error {
+		time.Sleep(fnDuration)
+		atomic.StoreInt32(&fnCompleted, 1)
+		return nil
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("RunWithLeaderElection returned error: %v", err)
+	}
+	if atomic.LoadInt32(&fnCompleted) != 1 {
+		t.Fatal("RunWithLeaderElection returned before fn completed")
+	}
+	if elapsed < fnDuration {
+		t.Fatalf("RunWithLeaderElection returned after %s, before fn's %s had a chance to complete", elapsed, fnDuration)
+	}
+}
+
+// TestRunWithLeaderElectionReturnsErrorWhenNeverAcquired covers the steady-state of every
+// non-leader replica: the lease is already held by someone else, so RunWithLeaderElection
+// never acquires it before ctx is cancelled and fn never runs. The caller must see a non-nil
+// error rather than a nil error indistinguishable from "fn ran and succeeded".
+func TestRunWithLeaderElectionReturnsErrorWhenNeverAcquired(t *testing.T) {
+	lockName := "test-lock"
+	namespace := "kube-system"
+	holder := "someone-else"
+	leaseDurationSeconds := int32(300)
+	existingLease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: lockName, Namespace: namespace},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &leaseDurationSeconds,
+			RenewTime:            &metav1.MicroTime{Time: time.Now()},
+		},
+	}
+
+	c := &kubernetesClientSetClient{
+		clientset: kubefake.NewSimpleClientset(existingLease),
+		interval:  10 * time.Millisecond,
+		timeout:   50 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	var fnCalled int32
+	err := c.RunWithLeaderElection(ctx, lockName, namespace, func(ctx context.Context) error {
+		atomic.StoreInt32(&fnCalled, 1)
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("expected a non-nil error when the lease was never acquired")
+	}
+	if atomic.LoadInt32(&fnCalled) != 0 {
+		t.Fatal("fn must not run when the lease is never acquired")
+	}
+}
+
+func TestLeaseDurations(t *testing.T) {
+	tests := []struct {
+		name              string
+		interval, timeout time.Duration
+	}{
+		{name: "defaults", interval: 0, timeout: 0},
+		{name: "short configured durations", interval: 1 * time.Second, timeout: 3 * time.Second},
+		{name: "interval larger than timeout", interval: 10 * time.Second, timeout: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaseDuration, renewDeadline, retryPeriod := leaseDurations(tt.interval, tt.timeout)
+
+			minRenewDeadline := time.Duration(float64(retryPeriod) * leaderElectionJitterFactor)
+			if renewDeadline <= minRenewDeadline {
+				t.Errorf("renewDeadline %s must exceed retryPeriod*JitterFactor %s", renewDeadline, minRenewDeadline)
+			}
+			if leaseDuration <= renewDeadline {
+				t.Errorf("leaseDuration %s must exceed renewDeadline %s", leaseDuration, renewDeadline)
+			}
+		})
+	}
+}
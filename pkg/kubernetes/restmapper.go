@@ -0,0 +1,24 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT license.
+
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+)
+
+// restMapper returns a RESTMapper built from the cluster's discovery information, used to
+// resolve an unstructured object's GroupVersionKind to the GroupVersionResource the dynamic
+// client needs to address it. The mapper is built once per client and cached, so a multi-
+// document apply amortizes a single discovery pass instead of re-fetching it per document;
+// DeferredDiscoveryRESTMapper itself resets and re-fetches on a mapping miss, so the cache
+// never goes stale for long.
+func (c *kubernetesClientSetClient) restMapper() meta.RESTMapper {
+	c.restMapperOnce.Do(func() {
+		cachedDiscovery := memory.NewMemCacheClient(c.clientset.Discovery())
+		c.cachedRESTMapper = restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+	})
+	return c.cachedRESTMapper
+}